@@ -0,0 +1,44 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package main
+
+import "fmt"
+
+// Error wraps an error occurring while running go-licenser together with
+// the exit code it should be reported with.
+type Error struct {
+	err  error
+	code int
+}
+
+// Error implements the error interface.
+func (e *Error) Error() string {
+	return fmt.Sprintf("%v", e.err)
+}
+
+// Code returns the exit code carried by err. It returns exitDefault for a
+// nil error or for any error not created by this package.
+func Code(err error) int {
+	if err == nil {
+		return exitDefault
+	}
+	if e, ok := err.(*Error); ok {
+		return e.code
+	}
+	return exitDefault
+}
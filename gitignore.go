@@ -0,0 +1,117 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package main
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// gitignore holds the patterns read from a single .gitignore file,
+// evaluated relative to the directory it was found in.
+type gitignore struct {
+	dir      string
+	patterns []ignorePattern
+}
+
+type ignorePattern struct {
+	pattern string
+	negate  bool
+}
+
+// loadGitignore reads dir/.gitignore, returning ok=false if it doesn't
+// exist or has no usable patterns.
+func loadGitignore(dir string) (gi *gitignore, ok bool) {
+	f, err := os.Open(filepath.Join(dir, ".gitignore"))
+	if err != nil {
+		return nil, false
+	}
+	defer f.Close()
+
+	gi = &gitignore{dir: dir}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		negate := strings.HasPrefix(line, "!")
+		if negate {
+			line = line[1:]
+		}
+		line = strings.TrimPrefix(line, "/")
+		line = strings.TrimSuffix(line, "/")
+
+		gi.patterns = append(gi.patterns, ignorePattern{pattern: line, negate: negate})
+	}
+
+	return gi, len(gi.patterns) > 0
+}
+
+// isGitignored reports whether path is ignored by any of gitignores, the
+// .gitignore files discovered so far while walking the tree.
+func isGitignored(gitignores []*gitignore, path string) bool {
+	for _, gi := range gitignores {
+		if gi.matches(path) {
+			return true
+		}
+	}
+	return false
+}
+
+// matches reports whether path, which must be under g.dir, is ignored by
+// g's patterns. Later patterns take precedence, matching git's own
+// last-match-wins semantics for negated patterns.
+func (g *gitignore) matches(path string) bool {
+	rel, err := filepath.Rel(g.dir, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return false
+	}
+	rel = filepath.ToSlash(rel)
+
+	ignored := false
+	for _, p := range g.patterns {
+		if patternMatchesRel(p.pattern, rel) {
+			ignored = !p.negate
+		}
+	}
+	return ignored
+}
+
+// patternMatchesRel reports whether pattern (a gitignore pattern, already
+// stripped of its leading/trailing slash) matches rel. A slash-free
+// pattern such as "vendor" is a directory-style pattern: it's checked
+// against every path segment, so it also matches files nested under a
+// same-named directory, the way git itself treats e.g. "vendor/".
+func patternMatchesRel(pattern, rel string) bool {
+	if globMatch(pattern, rel) || globMatch(pattern, filepath.Base(rel)) {
+		return true
+	}
+	if strings.Contains(pattern, "/") {
+		return false
+	}
+	for _, seg := range strings.Split(rel, "/") {
+		if globMatch(pattern, seg) {
+			return true
+		}
+	}
+	return false
+}
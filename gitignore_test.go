@@ -0,0 +1,77 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeGitignore(t *testing.T, dir, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, ".gitignore"), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGitignoreMatches(t *testing.T) {
+	dir := t.TempDir()
+	writeGitignore(t, dir, "*.log\nbuild/\n!build/keep.log\n")
+
+	gi, ok := loadGitignore(dir)
+	if !ok {
+		t.Fatal("loadGitignore: expected ok")
+	}
+
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{filepath.Join(dir, "a.log"), true},
+		{filepath.Join(dir, "a.go"), false},
+		{filepath.Join(dir, "build", "out.bin"), true},
+		{filepath.Join(dir, "build", "keep.log"), false},
+	}
+
+	for _, tt := range tests {
+		if got := gi.matches(tt.path); got != tt.want {
+			t.Errorf("matches(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestLoadGitignoreMissing(t *testing.T) {
+	dir := t.TempDir()
+	if _, ok := loadGitignore(dir); ok {
+		t.Fatal("loadGitignore: expected ok=false for a directory with no .gitignore")
+	}
+}
+
+func TestIsGitignored(t *testing.T) {
+	dir := t.TempDir()
+	writeGitignore(t, dir, "vendor/\n")
+	gi, _ := loadGitignore(dir)
+
+	if !isGitignored([]*gitignore{gi}, filepath.Join(dir, "vendor", "a.go")) {
+		t.Error("expected vendor/a.go to be ignored")
+	}
+	if isGitignored([]*gitignore{gi}, filepath.Join(dir, "a.go")) {
+		t.Error("expected a.go not to be ignored")
+	}
+}
@@ -0,0 +1,81 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package main
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+var globCache sync.Map // pattern string -> *regexp.Regexp
+
+// globMatch reports whether path matches pattern. Patterns use
+// doublestar-style globs: "**" matches across path separators, "*"
+// matches within a single path segment, and "?" matches a single
+// non-separator rune.
+func globMatch(pattern, path string) bool {
+	return globRegexp(pattern).MatchString(path)
+}
+
+func globRegexp(pattern string) *regexp.Regexp {
+	if re, ok := globCache.Load(pattern); ok {
+		return re.(*regexp.Regexp)
+	}
+
+	var b strings.Builder
+	b.WriteString("^")
+	for i := 0; i < len(pattern); {
+		switch {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			b.WriteString("(?:.*/)?")
+			i += 3
+		case strings.HasPrefix(pattern[i:], "**"):
+			b.WriteString(".*")
+			i += 2
+		case pattern[i] == '*':
+			b.WriteString("[^/]*")
+			i++
+		case pattern[i] == '?':
+			b.WriteString("[^/]")
+			i++
+		default:
+			b.WriteString(regexp.QuoteMeta(string(pattern[i])))
+			i++
+		}
+	}
+	b.WriteString("$")
+
+	re := regexp.MustCompile(b.String())
+	globCache.Store(pattern, re)
+	return re
+}
+
+// needsExclusion reports whether path matches any of the glob patterns,
+// either against the full path or its base name, so a pattern like
+// "*.pb.go" excludes matching files at any depth.
+func needsExclusion(path string, patterns []string) bool {
+	base := filepath.Base(path)
+	for _, pattern := range patterns {
+		if globMatch(pattern, path) || globMatch(pattern, base) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,66 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package main
+
+import "testing"
+
+func TestGlobMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{"*.pb.go", "a.pb.go", true},
+		{"*.pb.go", "dir/a.pb.go", false},
+		{"**/*.pb.go", "dir/a.pb.go", true},
+		{"**/*.pb.go", "a.pb.go", true},
+		{"**/generated/**", "a/generated/b.go", true},
+		{"**/generated/**", "generated/b.go", true},
+		{"**/generated/**", "a/other/b.go", false},
+		{"vendor/**", "vendor/pkg/a.go", true},
+		{"vendor/**", "notvendor/a.go", false},
+		{"a?.go", "ab.go", true},
+		{"a?.go", "abc.go", false},
+		{"a?.go", "a/b.go", false},
+	}
+
+	for _, tt := range tests {
+		if got := globMatch(tt.pattern, tt.path); got != tt.want {
+			t.Errorf("globMatch(%q, %q) = %v, want %v", tt.pattern, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestNeedsExclusion(t *testing.T) {
+	tests := []struct {
+		path     string
+		patterns []string
+		want     bool
+	}{
+		{"dir/a.pb.go", []string{"*.pb.go"}, true},
+		{"dir/a.go", []string{"*.pb.go"}, false},
+		{"a/generated/b.go", []string{"**/generated/**"}, true},
+		{"a/b.go", nil, false},
+	}
+
+	for _, tt := range tests {
+		if got := needsExclusion(tt.path, tt.patterns); got != tt.want {
+			t.Errorf("needsExclusion(%q, %v) = %v, want %v", tt.path, tt.patterns, got, tt.want)
+		}
+	}
+}
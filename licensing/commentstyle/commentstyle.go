@@ -0,0 +1,94 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package commentstyle maps file extensions to the comment syntax used to
+// embed a license header in that file type, so go-licenser isn't limited
+// to Go's "//" line comments.
+package commentstyle
+
+// Style describes how a header is embedded in a file: either as a run of
+// line comments (LinePrefix set), or as a single block comment (BlockOpen
+// and BlockClose set).
+type Style struct {
+	LinePrefix            string
+	BlockOpen, BlockClose string
+}
+
+// Styles maps a file extension, including the leading dot, to the Style
+// used for that file type.
+var Styles = map[string]Style{
+	".go":   {LinePrefix: "//"},
+	".rs":   {LinePrefix: "//"},
+	".js":   {LinePrefix: "//"},
+	".jsx":  {LinePrefix: "//"},
+	".ts":   {LinePrefix: "//"},
+	".tsx":  {LinePrefix: "//"},
+	".java": {LinePrefix: "//"},
+	".c":    {LinePrefix: "//"},
+	".h":    {LinePrefix: "//"},
+	".cc":   {LinePrefix: "//"},
+	".cpp":  {LinePrefix: "//"},
+
+	".py":   {LinePrefix: "#"},
+	".sh":   {LinePrefix: "#"},
+	".bash": {LinePrefix: "#"},
+	".rb":   {LinePrefix: "#"},
+	".yml":  {LinePrefix: "#"},
+	".yaml": {LinePrefix: "#"},
+	".toml": {LinePrefix: "#"},
+
+	".el":   {LinePrefix: ";"},
+	".lisp": {LinePrefix: ";"},
+	".clj":  {LinePrefix: ";"},
+
+	".html": {BlockOpen: "<!--", BlockClose: "-->"},
+	".htm":  {BlockOpen: "<!--", BlockClose: "-->"},
+	".md":   {BlockOpen: "<!--", BlockClose: "-->"},
+	".xml":  {BlockOpen: "<!--", BlockClose: "-->"},
+
+	".css":  {BlockOpen: "/*", BlockClose: "*/"},
+	".scss": {BlockOpen: "/*", BlockClose: "*/"},
+}
+
+// ForExt returns the Style registered for ext (including the leading
+// dot), and whether one was found.
+func ForExt(ext string) (Style, bool) {
+	s, ok := Styles[ext]
+	return s, ok
+}
+
+// Render wraps lines, the raw header text, in the comment syntax
+// described by s.
+func Render(s Style, lines []string) []string {
+	if s.BlockOpen != "" {
+		out := make([]string, 0, len(lines)+2)
+		out = append(out, s.BlockOpen)
+		out = append(out, lines...)
+		out = append(out, s.BlockClose)
+		return out
+	}
+
+	out := make([]string, len(lines))
+	for i, line := range lines {
+		if line == "" {
+			out[i] = s.LinePrefix
+			continue
+		}
+		out[i] = s.LinePrefix + " " + line
+	}
+	return out
+}
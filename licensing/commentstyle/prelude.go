@@ -0,0 +1,69 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package commentstyle
+
+import "bytes"
+
+var preludeLinePrefixes = [][]byte{
+	[]byte("//go:build"),
+	[]byte("// +build"),
+	[]byte("<?xml"),
+}
+
+// Prelude returns the number of leading bytes of content that must stay
+// first in the file: a shebang line and/or the lines listed in
+// preludeLinePrefixes (Go build constraints, XML prologs), plus the blank
+// line conventionally separating them from the rest of the file. The
+// license header is inserted after this offset instead of at byte 0.
+func Prelude(content []byte) int {
+	offset := 0
+	rest := content
+
+	if bytes.HasPrefix(rest, []byte("#!")) {
+		i := bytes.IndexByte(rest, '\n')
+		if i < 0 {
+			return len(content)
+		}
+		offset += i + 1
+		rest = rest[i+1:]
+	}
+
+	for hasAnyPrefix(rest, preludeLinePrefixes) {
+		i := bytes.IndexByte(rest, '\n')
+		if i < 0 {
+			return len(content)
+		}
+		offset += i + 1
+		rest = rest[i+1:]
+	}
+
+	if bytes.HasPrefix(rest, []byte("\n")) {
+		offset++
+	}
+
+	return offset
+}
+
+func hasAnyPrefix(b []byte, prefixes [][]byte) bool {
+	for _, p := range prefixes {
+		if bytes.HasPrefix(b, p) {
+			return true
+		}
+	}
+	return false
+}
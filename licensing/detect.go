@@ -0,0 +1,164 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package licensing
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/elastic/go-licenser/licensing/commentstyle"
+)
+
+// DefaultConfidenceThreshold is the similarity ratio, out of 1, that
+// callers of Detect should typically require before treating a match as
+// the file's actual header rather than coincidental text.
+const DefaultConfidenceThreshold = 0.9
+
+const spdxPrefix = "SPDX-License-Identifier:"
+
+// Detect scans the first lines of r for a header matching one of Headers,
+// or a bare SPDX-License-Identifier line, and returns the best-matching
+// license id, the confidence of that match as a ratio between 0 and 1,
+// and the [start, end) line span (0-indexed, end-exclusive) it occupies.
+// A zero-value span with confidence 0 means no header was found. Ties
+// between equally-scored headers are broken by sorting license ids, so
+// the result is deterministic regardless of map iteration order.
+func Detect(r io.Reader) (id string, confidence float64, span [2]int, err error) {
+	scanner := bufio.NewScanner(r)
+
+	var lines []string
+	for scanner.Scan() && len(lines) < maxScanLines {
+		lines = append(lines, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return "", 0, [2]int{}, err
+	}
+
+	for i, line := range lines {
+		if idx := strings.Index(line, spdxPrefix); idx >= 0 {
+			return strings.TrimSpace(line[idx+len(spdxPrefix):]), 1, [2]int{i, i + 1}, nil
+		}
+	}
+
+	headerIDs := make([]string, 0, len(Headers))
+	for headerID := range Headers {
+		headerIDs = append(headerIDs, headerID)
+	}
+	sort.Strings(headerIDs)
+
+	var bestID string
+	var bestScore float64
+	var bestSpan [2]int
+	for _, headerID := range headerIDs {
+		score, matchSpan := headerMatchScore(lines, Headers[headerID])
+		if score > bestScore {
+			bestScore, bestID, bestSpan = score, headerID, matchSpan
+		}
+	}
+
+	return bestID, bestScore, bestSpan, nil
+}
+
+// headerMatchScore finds the span within lines that best matches header,
+// ignoring the comment markers each line is wrapped in and any copyright
+// year, and returns the fraction of header lines that matched there.
+func headerMatchScore(lines, header []string) (float64, [2]int) {
+	var best float64
+	var bestSpan [2]int
+
+	for start := 0; start+len(header) <= len(lines); start++ {
+		var matched int
+		for i, want := range header {
+			got := stripCommentMarkers(lines[start+i])
+			if strings.Contains(want, "{{") || normalizeYear(got) == normalizeYear(want) {
+				matched++
+			}
+		}
+
+		score := float64(matched) / float64(len(header))
+		if score > best {
+			best = score
+			bestSpan = [2]int{start, start + len(header)}
+		}
+	}
+
+	return best, bestSpan
+}
+
+var commentMarkers = []string{"//", "#", ";", "/*", "*/", "<!--", "-->"}
+
+// stripCommentMarkers removes a leading comment marker and the single
+// space commentstyle.Render inserts after it, preserving any further
+// indentation that's part of the header text itself (e.g. BSD-3-Clause's
+// indented bullet continuation lines).
+func stripCommentMarkers(line string) string {
+	trimmed := strings.TrimLeft(line, " \t")
+	for _, marker := range commentMarkers {
+		if after, ok := strings.CutPrefix(trimmed, marker); ok {
+			return strings.TrimPrefix(after, " ")
+		}
+	}
+	return trimmed
+}
+
+// ReplaceFileHeader overwrites the header occupying span (as returned by
+// Detect, relative to the first line after any shebang/build-constraint
+// prelude) with header.
+func ReplaceFileHeader(path string, header []byte, span [2]int) error {
+	orig, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	offset := commentstyle.Prelude(orig)
+	body := orig[offset:]
+
+	lineStarts := []int{0}
+	for i, b := range body {
+		if b == '\n' {
+			lineStarts = append(lineStarts, i+1)
+		}
+	}
+	lineStarts = append(lineStarts, len(body))
+
+	if span[0] < 0 || span[1] < span[0] || span[1] >= len(lineStarts) {
+		return fmt.Errorf("licensing: invalid header span %v for %d lines", span, len(lineStarts)-1)
+	}
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_TRUNC, 0)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if _, err := f.Write(orig[:offset]); err != nil {
+		return err
+	}
+	if _, err := f.Write(body[:lineStarts[span[0]]]); err != nil {
+		return err
+	}
+	if _, err := f.Write(header); err != nil {
+		return err
+	}
+	_, err = f.Write(body[lineStarts[span[1]]:])
+	return err
+}
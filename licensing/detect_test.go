@@ -0,0 +1,162 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package licensing
+
+import (
+	"strings"
+	"testing"
+)
+
+func renderedASL2(t *testing.T) []string {
+	t.Helper()
+	rendered, err := RenderHeader(Headers["ASL2"], TemplateVars{Year: "2024", Holder: "Test Holder"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return rendered
+}
+
+func commentOut(lines []string) string {
+	var b strings.Builder
+	for _, l := range lines {
+		if l == "" {
+			b.WriteString("//\n")
+			continue
+		}
+		b.WriteString("// " + l + "\n")
+	}
+	return b.String()
+}
+
+func TestContainsHeaderIgnoresYear(t *testing.T) {
+	header, err := RenderHeader(Headers["MIT"], TemplateVars{Year: "2024", Holder: "Test Holder"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	commented := commentOut(header)
+	file := commented + "\npackage p\n"
+
+	if !ContainsHeader(strings.NewReader(file), strings.Split(strings.TrimRight(commented, "\n"), "\n"), "") {
+		t.Fatal("expected exact header to match")
+	}
+
+	bumped, err := RenderHeader(Headers["MIT"], TemplateVars{Year: "2099", Holder: "Test Holder"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	bumpedCommented := commentOut(bumped)
+	if !ContainsHeader(strings.NewReader(file), strings.Split(strings.TrimRight(bumpedCommented, "\n"), "\n"), "") {
+		t.Fatal("expected header with a different year to still match")
+	}
+}
+
+func TestContainsHeaderSPDXLine(t *testing.T) {
+	header := strings.Split(strings.TrimRight(commentOut(renderedASL2(t)), "\n"), "\n")
+	file := "// " + SPDXLine("Apache-2.0") + "\n\npackage p\n"
+	if !ContainsHeader(strings.NewReader(file), header, "Apache-2.0") {
+		t.Fatal("expected SPDX identifier line to satisfy ContainsHeader")
+	}
+	if ContainsHeader(strings.NewReader(file), header, "MIT") {
+		t.Fatal("expected mismatched SPDX identifier not to match")
+	}
+}
+
+func TestContainsHeaderMissing(t *testing.T) {
+	header := strings.Split(strings.TrimRight(commentOut(renderedASL2(t)), "\n"), "\n")
+	if ContainsHeader(strings.NewReader("package p\n"), header, "") {
+		t.Fatal("expected a file with no header not to match")
+	}
+}
+
+func TestDetectFindsKnownHeader(t *testing.T) {
+	file := commentOut(renderedASL2(t)) + "\npackage p\n"
+
+	id, confidence, span, err := Detect(strings.NewReader(file))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != "ASL2" {
+		t.Errorf("id = %q, want ASL2", id)
+	}
+	if confidence < DefaultConfidenceThreshold {
+		t.Errorf("confidence = %v, want >= %v", confidence, DefaultConfidenceThreshold)
+	}
+	if span[0] != 0 || span[1] != len(Headers["ASL2"]) {
+		t.Errorf("span = %v, want [0, %d)", span, len(Headers["ASL2"]))
+	}
+}
+
+func TestDetectFindsSPDXLine(t *testing.T) {
+	id, confidence, span, err := Detect(strings.NewReader("// " + SPDXLine("MIT") + "\n\npackage p\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != "MIT" {
+		t.Errorf("id = %q, want MIT", id)
+	}
+	if confidence != 1 {
+		t.Errorf("confidence = %v, want 1", confidence)
+	}
+	if span != [2]int{0, 1} {
+		t.Errorf("span = %v, want [0, 1)", span)
+	}
+}
+
+// TestAllHeadersFitWithinScanWindow is a regression test for every entry
+// in Headers, not just ASL2: each must be both recognized by
+// ContainsHeader and detected by Detect once rendered and commented, so
+// a future header longer than maxScanLines can't silently fall outside
+// the scanned prefix and be reported as missing on every run.
+func TestAllHeadersFitWithinScanWindow(t *testing.T) {
+	for id, header := range Headers {
+		id, header := id, header
+		t.Run(id, func(t *testing.T) {
+			rendered, err := RenderHeader(header, TemplateVars{Year: "2024", Holder: "Test Holder"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			commented := strings.Split(strings.TrimRight(commentOut(rendered), "\n"), "\n")
+			file := commentOut(rendered) + "\npackage p\n"
+
+			if !ContainsHeader(strings.NewReader(file), commented, "") {
+				t.Errorf("ContainsHeader: %s header (%d lines) not recognized within a %d-line scan window", id, len(header), maxScanLines)
+			}
+
+			detectedID, confidence, _, err := Detect(strings.NewReader(file))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if detectedID != id {
+				t.Errorf("Detect: id = %q, want %q", detectedID, id)
+			}
+			if confidence < DefaultConfidenceThreshold {
+				t.Errorf("Detect: confidence = %v, want >= %v", confidence, DefaultConfidenceThreshold)
+			}
+		})
+	}
+}
+
+func TestDetectNoHeader(t *testing.T) {
+	_, confidence, _, err := Detect(strings.NewReader("package p\n\nfunc main() {}\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if confidence >= DefaultConfidenceThreshold {
+		t.Errorf("confidence = %v, want < %v for a file with no header", confidence, DefaultConfidenceThreshold)
+	}
+}
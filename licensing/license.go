@@ -0,0 +1,217 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package licensing holds the license header texts go-licenser knows how
+// to check for and apply, along with the helpers used to detect and
+// rewrite them in source files.
+package licensing
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/elastic/go-licenser/licensing/commentstyle"
+)
+
+// scanLineSlack is added on top of the longest entry in Headers when
+// computing maxScanLines, to leave room for a blank line or two before
+// the header starts.
+const scanLineSlack = 4
+
+// maxScanLines bounds how many lines of a file ContainsHeader and Detect
+// inspect when looking for a header or an SPDX identifier line. It's
+// derived from Headers rather than hardcoded, so adding a longer header
+// can't silently make it unmatchable within the scanned prefix.
+var maxScanLines = longestHeaderLen() + scanLineSlack
+
+func longestHeaderLen() int {
+	var n int
+	for _, header := range Headers {
+		if len(header) > n {
+			n = len(header)
+		}
+	}
+	return n
+}
+
+// Headers maps a license identifier to the lines of its raw header text,
+// without any comment markers. Each line is parsed as a text/template and
+// rendered with a TemplateVars (see RenderHeader), then wrapped in the
+// comment syntax of the target file by commentstyle.Render.
+var Headers = map[string][]string{
+	"ASL2": {
+		"Licensed to Elasticsearch B.V. under one or more contributor",
+		"license agreements. See the NOTICE file distributed with",
+		"this work for additional information regarding copyright",
+		"ownership. {{.Holder}} licenses this file to you under",
+		"the Apache License, Version 2.0 (the \"License\"); you may",
+		"not use this file except in compliance with the License.",
+		"You may obtain a copy of the License at",
+		"",
+		"    http://www.apache.org/licenses/LICENSE-2.0",
+		"",
+		"Unless required by applicable law or agreed to in writing,",
+		"software distributed under the License is distributed on an",
+		"\"AS IS\" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY",
+		"KIND, either express or implied.  See the License for the",
+		"specific language governing permissions and limitations",
+		"under the License.",
+	},
+	"MIT": {
+		"Copyright (c) {{.Year}} {{.Holder}}",
+		"",
+		"Permission is hereby granted, free of charge, to any person obtaining a copy",
+		"of this software and associated documentation files (the \"Software\"), to deal",
+		"in the Software without restriction, including without limitation the rights",
+		"to use, copy, modify, merge, publish, distribute, sublicense, and/or sell",
+		"copies of the Software, and to permit persons to whom the Software is",
+		"furnished to do so, subject to the following conditions:",
+		"",
+		"The above copyright notice and this permission notice shall be included in",
+		"all copies or substantial portions of the Software.",
+		"",
+		"THE SOFTWARE IS PROVIDED \"AS IS\", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR",
+		"IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,",
+		"FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE",
+		"AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER",
+		"LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,",
+		"OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN",
+		"THE SOFTWARE.",
+	},
+	"BSD-3-Clause": {
+		"Copyright (c) {{.Year}} {{.Holder}}",
+		"All rights reserved.",
+		"",
+		"Redistribution and use in source and binary forms, with or without",
+		"modification, are permitted provided that the following conditions are met:",
+		"",
+		"  * Redistributions of source code must retain the above copyright",
+		"    notice, this list of conditions and the following disclaimer.",
+		"  * Redistributions in binary form must reproduce the above copyright",
+		"    notice, this list of conditions and the following disclaimer in the",
+		"    documentation and/or other materials provided with the distribution.",
+		"  * Neither the name of {{.Holder}} nor the names of its contributors may",
+		"    be used to endorse or promote products derived from this software",
+		"    without specific prior written permission.",
+		"",
+		"THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS \"AS IS\"",
+		"AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE",
+		"IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE",
+		"ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE",
+		"LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR",
+		"CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF",
+		"SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS",
+		"INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN",
+		"CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)",
+		"ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE",
+		"POSSIBILITY OF SUCH DAMAGE.",
+	},
+	"MPL-2.0": {
+		"This Source Code Form is subject to the terms of the Mozilla Public",
+		"License, v. 2.0. If a copy of the MPL was not distributed with this",
+		"file, You can obtain one at http://mozilla.org/MPL/2.0/.",
+	},
+	"GPL-3.0": {
+		"Copyright (c) {{.Year}} {{.Holder}}",
+		"",
+		"This program is free software: you can redistribute it and/or modify",
+		"it under the terms of the GNU General Public License as published by",
+		"the Free Software Foundation, either version 3 of the License, or",
+		"(at your option) any later version.",
+		"",
+		"This program is distributed in the hope that it will be useful,",
+		"but WITHOUT ANY WARRANTY; without even the implied warranty of",
+		"MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the",
+		"GNU General Public License for more details.",
+		"",
+		"You should have received a copy of the GNU General Public License",
+		"along with this program.  If not, see <https://www.gnu.org/licenses/>.",
+	},
+}
+
+// yearPattern matches a copyright year or year range (e.g. 2018 or
+// 2018-2024), so header comparisons can ignore it.
+var yearPattern = regexp.MustCompile(`\b\d{4}(-\d{4})?\b`)
+
+func normalizeYear(s string) string {
+	return yearPattern.ReplaceAllString(s, "YEAR")
+}
+
+// ContainsHeader reports whether r already carries header (a rendered
+// header, as returned by RenderHeader), or an SPDX identifier line naming
+// spdxID. spdxID may be empty, in which case only the full header is
+// checked. The comparison ignores copyright years, so bumping the year in
+// a template doesn't cause every file to be treated as missing its header.
+func ContainsHeader(r io.Reader, header []string, spdxID string) bool {
+	scanner := bufio.NewScanner(r)
+
+	var lines []string
+	for scanner.Scan() && len(lines) < maxScanLines {
+		lines = append(lines, scanner.Text())
+	}
+
+	if spdxID != "" {
+		want := "SPDX-License-Identifier: " + spdxID
+		for _, line := range lines {
+			if strings.Contains(line, want) {
+				return true
+			}
+		}
+	}
+
+	if len(lines) < len(header) {
+		return false
+	}
+
+	for i, want := range header {
+		if normalizeYear(lines[i]) != normalizeYear(want) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// RewriteFileWithHeader inserts header into the file at path, after any
+// shebang line or build-constraint prelude that must stay first in the
+// file (see commentstyle.Prelude).
+func RewriteFileWithHeader(path string, header []byte) error {
+	orig, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	offset := commentstyle.Prelude(orig)
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_TRUNC, 0)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if _, err := f.Write(orig[:offset]); err != nil {
+		return err
+	}
+	if _, err := f.Write(header); err != nil {
+		return err
+	}
+	_, err = f.Write(orig[offset:])
+	return err
+}
@@ -0,0 +1,60 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package licensing
+
+// SPDXAliases maps go-licenser's legacy license keys to their SPDX
+// identifier equivalent, so e.g. -license ASL2 keeps resolving to the
+// right SPDX-License-Identifier once a file only carries the short form.
+var SPDXAliases = map[string]string{
+	"ASL2": "Apache-2.0",
+}
+
+// legacyAliases is the inverse of SPDXAliases, letting callers pass an
+// SPDX identifier such as "Apache-2.0" where a legacy key like "ASL2" is
+// expected.
+var legacyAliases = reverse(SPDXAliases)
+
+func reverse(m map[string]string) map[string]string {
+	out := make(map[string]string, len(m))
+	for k, v := range m {
+		out[v] = k
+	}
+	return out
+}
+
+// ResolveLicense normalizes license, which may be a legacy key (ASL2), a
+// known SPDX identifier (Apache-2.0), or an arbitrary SPDX identifier
+// go-licenser has no full header text for. It returns the key to look up
+// in Headers and the SPDX identifier to use for short-form headers and
+// detection.
+func ResolveLicense(license string) (headerKey, spdxID string) {
+	if id, ok := SPDXAliases[license]; ok {
+		return license, id
+	}
+	if key, ok := legacyAliases[license]; ok {
+		return key, license
+	}
+	return license, license
+}
+
+// SPDXLine returns the raw (uncommented) short-form SPDX header line for
+// id, e.g. "SPDX-License-Identifier: Apache-2.0". Callers wrap it in the
+// target file's comment syntax, e.g. via commentstyle.Render.
+func SPDXLine(id string) string {
+	return "SPDX-License-Identifier: " + id
+}
@@ -0,0 +1,63 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package licensing
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"text/template"
+)
+
+// TemplateVars holds the values a header template may reference.
+type TemplateVars struct {
+	Year    string
+	Holder  string
+	Project string
+	SPDXID  string
+}
+
+// RenderHeader parses each of lines as a text/template and executes it
+// against vars, returning the rendered lines in the same order.
+func RenderHeader(lines []string, vars TemplateVars) ([]string, error) {
+	rendered := make([]string, len(lines))
+	for i, line := range lines {
+		tmpl, err := template.New("header").Parse(line)
+		if err != nil {
+			return nil, err
+		}
+
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, vars); err != nil {
+			return nil, err
+		}
+		rendered[i] = buf.String()
+	}
+	return rendered, nil
+}
+
+// LoadTemplate reads a custom header template from path, treating each
+// line of the file as one line of the header, in the same form as the
+// entries in Headers.
+func LoadTemplate(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return strings.Split(strings.TrimRight(string(data), "\n"), "\n"), nil
+}
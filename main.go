@@ -18,16 +18,22 @@
 package main
 
 import (
+	"bytes"
 	"flag"
 	"fmt"
 	"io"
 	"io/fs"
 	"os"
 	"path/filepath"
+	"runtime"
 	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/elastic/go-licenser/licensing"
+	"github.com/elastic/go-licenser/licensing/commentstyle"
 )
 
 const (
@@ -47,6 +53,7 @@ const (
 	exitFailedToOpenWalkFile
 	errFailedRewrittingFile
 	errUnknownLicense
+	errUnknownFormat
 )
 
 var usageText = `
@@ -62,11 +69,19 @@ Options:
 var (
 	dryRun             bool
 	showVersion        bool
-	extension          string
+	extensions         extFlag
 	args               []string
 	license            string
 	licensor           string
-	exclude            sliceFlag
+	year               string
+	templateFile       string
+	spdx               bool
+	jobs               int
+	format             string
+	replace            bool
+	confidence         float64
+	ignorePatterns     sliceFlag
+	noGitignore        bool
 	defaultExludedDirs = []string{"vendor", ".git"}
 )
 
@@ -85,6 +100,29 @@ func (f *sliceFlag) Set(value string) error {
 	return nil
 }
 
+// extFlag is like sliceFlag but also splits comma-separated values, so
+// -ext can be passed either repeatably (-ext .go -ext .java) or as a
+// single comma-separated list (-ext .go,.java).
+type extFlag []string
+
+func (f *extFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *extFlag) Set(value string) error {
+	for _, ext := range strings.Split(value, ",") {
+		ext = strings.TrimSpace(ext)
+		if ext == "" {
+			continue
+		}
+		if !strings.HasPrefix(ext, ".") {
+			ext = "." + ext
+		}
+		*f = append(*f, ext)
+	}
+	return nil
+}
+
 func initFlags() {
 	var licenseTypes []string
 	for k := range licensing.Headers {
@@ -92,12 +130,22 @@ func initFlags() {
 	}
 	sort.Strings(licenseTypes)
 
-	flag.Var(&exclude, "exclude", `path to exclude (can be specified multiple times).`)
+	flag.Var(&ignorePatterns, "ignore", `glob pattern to exclude, e.g. "**/generated/**" or "*.pb.go" (can be specified multiple times).`)
+	flag.Var(&ignorePatterns, "exclude", `alias of -ignore.`)
+	flag.BoolVar(&noGitignore, "no-gitignore", false, `disables automatic exclusion of files ignored by .gitignore.`)
 	flag.BoolVar(&dryRun, "d", false, `skips rewriting files and returns exitcode 1 if any discrepancies are found.`)
 	flag.BoolVar(&showVersion, "version", false, `prints out the binary version.`)
-	flag.StringVar(&extension, "ext", defaultExt, "sets the file extension to scan for.")
+	flag.Var(&extensions, "ext", "sets the file extension(s) to scan for: comma-separated, or repeatable (default .go).")
 	flag.StringVar(&license, "license", defaultLicense, fmt.Sprintf("sets the license type to check: %s", strings.Join(licenseTypes, ", ")))
 	flag.StringVar(&licensor, "licensor", defaultLicensor, "sets the name of the licensor")
+	flag.StringVar(&licensor, "holder", defaultLicensor, "alias of -licensor.")
+	flag.StringVar(&year, "year", strconv.Itoa(time.Now().Year()), "sets the copyright year, or range (e.g. 2018-2024), available to header templates as {{.Year}}.")
+	flag.StringVar(&templateFile, "template", "", "loads a custom header template from the given file instead of a built-in license.")
+	flag.BoolVar(&spdx, "spdx", false, "writes a minimal SPDX-License-Identifier header instead of the full license text.")
+	flag.IntVar(&jobs, "j", 0, "sets the number of files processed in parallel (default: number of CPUs).")
+	flag.StringVar(&format, "format", "text", "sets the dry-run report format: text, json, sarif.")
+	flag.BoolVar(&replace, "replace", false, "replaces an existing but mismatched license header in-place instead of prepending a new one.")
+	flag.Float64Var(&confidence, "confidence", licensing.DefaultConfidenceThreshold, "sets the similarity ratio, between 0 and 1, required to treat an existing header as a match for -replace.")
 	flag.Usage = usageFlag
 	flag.Parse()
 	args = flag.Args()
@@ -111,7 +159,12 @@ func main() {
 		return
 	}
 
-	err := run(args, license, licensor, exclude, extension, dryRun, os.Stdout)
+	exts := []string(extensions)
+	if len(exts) == 0 {
+		exts = []string{defaultExt}
+	}
+
+	err := run(args, license, licensor, year, templateFile, ignorePatterns, exts, dryRun, spdx, jobs, noGitignore, format, replace, confidence, os.Stdout)
 	if err != nil && err.Error() != "<nil>" {
 		fmt.Fprint(os.Stderr, err)
 	}
@@ -119,20 +172,39 @@ func main() {
 	os.Exit(Code(err))
 }
 
-func run(args []string, license, licensor string, exclude []string, ext string, dry bool, out io.Writer) error {
-	header, ok := licensing.Headers[license]
-	if !ok {
-		return &Error{err: fmt.Errorf("unknown license: %s", license), code: errUnknownLicense}
-	}
+// headerSpec is the raw, already template-rendered header text for a run,
+// with no comment markers applied yet. Each file gets it commented in the
+// style matching its own extension; see headerSpec.render.
+type headerSpec struct {
+	lines []string
+	// headerKey is the resolved Headers map key for the expected license
+	// (e.g. "ASL2"), as returned by licensing.ResolveLicense and by
+	// licensing.Detect. Comparing against it, rather than the raw
+	// -license flag value, keeps malformed-header detection correct
+	// regardless of which alias spelling of a license was passed.
+	headerKey string
+	spdxID    string
+	// label is a human-readable name for the expected license, used in
+	// dry-run reports.
+	label string
+}
 
-	var headerBytes []byte
-	for i, line := range header {
-		if strings.Contains(line, "%s") {
-			header[i] = fmt.Sprintf(line, licensor)
-		}
-		headerBytes = append(headerBytes, []byte(header[i])...)
-		headerBytes = append(headerBytes, []byte("\n")...)
+// render wraps h in the comment syntax for style and returns the bytes to
+// write to, or compare against, a file using that style.
+func (h headerSpec) render(style commentstyle.Style) []byte {
+	commented := commentstyle.Render(style, h.lines)
+	commented = append(commented, "")
+
+	var buf []byte
+	for _, line := range commented {
+		buf = append(buf, line...)
+		buf = append(buf, '\n')
 	}
+	return buf
+}
+
+func run(args []string, license, licensor, year, templateFile string, ignore, exts []string, dry, spdx bool, workers int, noGitignore bool, format string, replace bool, confidence float64, out io.Writer) error {
+	headerKey, spdxID := licensing.ResolveLicense(license)
 
 	var path = defaultPath
 	if len(args) > 0 {
@@ -143,24 +215,89 @@ func run(args []string, license, licensor string, exclude []string, ext string,
 		return &Error{err: err, code: exitFailedToStatTree}
 	}
 
-	return walk(path, ext, license, headerBytes, exclude, dry, out)
+	reporter, err := reporterFor(format)
+	if err != nil {
+		return &Error{err: err, code: errUnknownFormat}
+	}
+
+	spec := headerSpec{headerKey: headerKey, spdxID: spdxID, label: license}
+	if spdx {
+		spec.lines = []string{licensing.SPDXLine(spdxID)}
+	} else {
+		var header []string
+		if templateFile != "" {
+			var err error
+			header, err = licensing.LoadTemplate(templateFile)
+			if err != nil {
+				return &Error{err: err, code: exitFailedToOpenWalkFile}
+			}
+		} else {
+			var ok bool
+			header, ok = licensing.Headers[headerKey]
+			if !ok {
+				return &Error{err: fmt.Errorf("unknown license: %s", license), code: errUnknownLicense}
+			}
+		}
+
+		vars := licensing.TemplateVars{
+			Year:    year,
+			Holder:  licensor,
+			Project: projectName(path),
+			SPDXID:  spdxID,
+		}
+
+		rendered, err := licensing.RenderHeader(header, vars)
+		if err != nil {
+			return &Error{err: err, code: errUnknownLicense}
+		}
+		spec.lines = rendered
+	}
+
+	return walk(path, exts, spec, ignore, dry, workers, noGitignore, replace, confidence, reporter, out)
+}
+
+// projectName derives a default {{.Project}} value from the directory
+// being scanned, so header templates can reference it without requiring
+// an extra flag.
+func projectName(path string) string {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return ""
+	}
+	return filepath.Base(abs)
 }
 
-func reportFile(out io.Writer, f string) {
+// relPath renders f relative to the directory holding the running
+// binary (os.Args[0]) for reporting,
+// falling back to f itself if that's not possible.
+func relPath(f string) string {
 	cwd, _ := filepath.Abs(filepath.Dir(os.Args[0]))
 	rel, err := filepath.Rel(cwd, f)
 	if err != nil {
-		rel = f
+		return f
 	}
-	fmt.Fprintf(out, defaultFormat, rel)
+	return rel
 }
 
-func walk(p, ext, license string, headerBytes []byte, exclude []string, dry bool, out io.Writer) error {
-	var err error
-	filepath.WalkDir(p, func(path string, info fs.DirEntry, walkErr error) error {
-		if walkErr != nil {
-			err = &Error{err: walkErr, code: exitFailedToWalkPath}
-			return walkErr
+// fileResult is the outcome of processing a single file: a reportResult
+// together with an exitSourceNeedsToBeRewritten error in dry-run mode, an
+// error alone if the file couldn't be read or rewritten, or neither if the
+// file already has a matching header or was rewritten successfully.
+type fileResult struct {
+	result reportResult
+	err    *Error
+}
+
+// walk discovers the files under p matching exts, then checks or rewrites
+// their license headers using a pool of workers goroutines. Results are
+// collected and, for dry-run mode, sorted before being handed to reporter,
+// so the report is stable regardless of which worker finished first.
+func walk(p string, exts []string, spec headerSpec, ignore []string, dry bool, workers int, noGitignore, replace bool, confidence float64, reporter Reporter, out io.Writer) error {
+	var paths []string
+	var gitignores []*gitignore
+	walkErr := filepath.WalkDir(p, func(path string, info fs.DirEntry, err error) error {
+		if err != nil {
+			return err
 		}
 
 		var currentPath = cleanPathPrefixes(
@@ -169,45 +306,136 @@ func walk(p, ext, license string, headerBytes []byte, exclude []string, dry bool
 		)
 
 		var excludedDir = info.IsDir() && stringInSlice(info.Name(), defaultExludedDirs)
-		if needsExclusion(currentPath, exclude) || excludedDir {
-			return filepath.SkipDir
+		if needsExclusion(currentPath, ignore) || excludedDir || isGitignored(gitignores, path) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if info.IsDir() {
+			if !noGitignore {
+				if gi, ok := loadGitignore(path); ok {
+					gitignores = append(gitignores, gi)
+				}
+			}
+			return nil
 		}
 
-		if e := addOrCheckLicense(path, ext, license, headerBytes, info, dry, out); e != nil {
-			err = e
+		if stringInSlice(filepath.Ext(path), exts) {
+			paths = append(paths, path)
 		}
 
 		return nil
 	})
+	if walkErr != nil {
+		return &Error{err: walkErr, code: exitFailedToWalkPath}
+	}
+
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	if workers > len(paths) {
+		workers = len(paths)
+	}
+	if workers < 1 {
+		workers = 1
+	}
+
+	jobs := make(chan string)
+	results := make(chan fileResult)
+
+	var workerGroup sync.WaitGroup
+	workerGroup.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer workerGroup.Done()
+			for path := range jobs {
+				results <- processFile(path, spec, dry, replace, confidence)
+			}
+		}()
+	}
+
+	go func() {
+		for _, path := range paths {
+			jobs <- path
+		}
+		close(jobs)
+	}()
+
+	go func() {
+		workerGroup.Wait()
+		close(results)
+	}()
+
+	var reports []reportResult
+	var worst *Error
+	for res := range results {
+		if res.result.Path != "" {
+			reports = append(reports, res.result)
+		}
+		if res.err != nil && (worst == nil || res.err.code > worst.code) {
+			worst = res.err
+		}
+	}
 
-	return err
+	sort.Slice(reports, func(i, j int) bool { return reports[i].Path < reports[j].Path })
+	if err := reporter.Report(out, reports); err != nil {
+		return &Error{err: err, code: errFailedRewrittingFile}
+	}
+
+	if worst != nil {
+		return worst
+	}
+	return nil
 }
 
-func addOrCheckLicense(path, ext, license string, headerBytes []byte, info fs.DirEntry, dry bool, out io.Writer) error {
-	if info.IsDir() || filepath.Ext(path) != ext {
-		return nil
+func processFile(path string, spec headerSpec, dry, replace bool, confidence float64) fileResult {
+	style, ok := commentstyle.ForExt(filepath.Ext(path))
+	if !ok {
+		style = commentstyle.Style{LinePrefix: "//"}
 	}
+	headerBytes := spec.render(style)
 
-	f, e := os.Open(path)
+	content, e := os.ReadFile(path)
 	if e != nil {
-		return &Error{err: e, code: exitFailedToOpenWalkFile}
+		return fileResult{err: &Error{err: e, code: exitFailedToOpenWalkFile}}
 	}
-	defer f.Close()
 
-	if licensing.ContainsHeader(f, licensing.Headers[license]) {
-		return nil
+	body := content[commentstyle.Prelude(content):]
+	headerLines := strings.Split(strings.TrimRight(string(headerBytes), "\n"), "\n")
+	if licensing.ContainsHeader(bytes.NewReader(body), headerLines, spec.spdxID) {
+		return fileResult{}
 	}
 
+	detectedID, detectedConfidence, span, _ := licensing.Detect(bytes.NewReader(body))
+	malformed := detectedConfidence >= confidence && detectedID != spec.spdxID && detectedID != spec.headerKey
+
 	if dry {
-		reportFile(out, path)
-		return &Error{code: exitSourceNeedsToBeRewritten}
+		if malformed {
+			return fileResult{
+				result: reportResult{Path: relPath(path), Status: statusMalformed, Expected: spec.label, Detected: detectedID},
+				err:    &Error{code: exitSourceNeedsToBeRewritten},
+			}
+		}
+		return fileResult{
+			result: reportResult{Path: relPath(path), Status: statusMissing, Expected: spec.label},
+			err:    &Error{code: exitSourceNeedsToBeRewritten},
+		}
+	}
+
+	if malformed && replace {
+		if err := licensing.ReplaceFileHeader(path, headerBytes, span); err != nil {
+			return fileResult{err: &Error{err: err, code: errFailedRewrittingFile}}
+		}
+		return fileResult{}
 	}
 
 	if err := licensing.RewriteFileWithHeader(path, headerBytes); err != nil {
-		return &Error{err: err, code: errFailedRewrittingFile}
+		return fileResult{err: &Error{err: err, code: errFailedRewrittingFile}}
 	}
 
-	return nil
+	return fileResult{}
 }
 
 func stringInSlice(a string, list []string) bool {
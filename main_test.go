@@ -0,0 +1,107 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestWalkWorkerPoolProcessesAllFiles exercises walk with more files than
+// workers, checking that every file is still visited exactly once and
+// that the dry-run report is sorted regardless of which worker finished
+// first.
+func TestWalkWorkerPoolProcessesAllFiles(t *testing.T) {
+	dir := t.TempDir()
+	const n = 20
+	for i := 0; i < n; i++ {
+		name := fmt.Sprintf("file%02d.go", i)
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("package p\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var out bytes.Buffer
+	err := run([]string{dir}, "ASL2", "Test Holder", "2024", "",
+		nil, []string{".go"},
+		true /* dry */, false, 2 /* workers */, true /* noGitignore */, "json", false, 0.9, &out)
+	if err == nil {
+		t.Fatal("expected every file to be reported as missing its header")
+	}
+
+	for i := 0; i < n; i++ {
+		name := fmt.Sprintf("file%02d.go", i)
+		if !strings.Contains(out.String(), name) {
+			t.Errorf("report missing %s", name)
+		}
+	}
+
+	var paths []string
+	for _, line := range strings.Split(out.String(), "\n") {
+		const key = `"path": "`
+		idx := strings.Index(line, key)
+		if idx < 0 {
+			continue
+		}
+		rest := line[idx+len(key):]
+		paths = append(paths, rest[:strings.Index(rest, `"`)])
+	}
+
+	if len(paths) != n {
+		t.Fatalf("got %d reported paths, want %d", len(paths), n)
+	}
+	for i := 1; i < len(paths); i++ {
+		if paths[i] < paths[i-1] {
+			t.Errorf("report is not sorted: %q came after %q", paths[i], paths[i-1])
+		}
+	}
+}
+
+// TestWalkAggregatesWorstError checks that walk reports the
+// highest-severity error across all files rather than racing on a
+// shared variable, by mixing a file that needs rewriting with one
+// go-licenser can't even read.
+func TestWalkAggregatesWorstError(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.go"), []byte("package p\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	// A dangling symlink is unreadable regardless of the running user's
+	// privileges, unlike a merely permission-denied file.
+	unreadable := filepath.Join(dir, "b.go")
+	if err := os.Symlink(filepath.Join(dir, "does-not-exist"), unreadable); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	err := run([]string{dir}, "ASL2", "Test Holder", "2024", "",
+		nil, []string{".go"},
+		true, false, 4, true, "text", false, 0.9, &out)
+
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	code := Code(err)
+	if code != exitFailedToOpenWalkFile {
+		t.Errorf("exit code = %d, want %d (exitFailedToOpenWalkFile)", code, exitFailedToOpenWalkFile)
+	}
+}
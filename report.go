@@ -0,0 +1,90 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+const (
+	statusOK        = "ok"
+	statusMissing   = "missing"
+	statusMalformed = "malformed"
+	statusRewritten = "rewritten"
+)
+
+// reportResult is a file's outcome in dry-run mode: whether it carries
+// the expected license header, and if not, what was expected instead.
+type reportResult struct {
+	Path     string `json:"path"`
+	Status   string `json:"status"`
+	Expected string `json:"expected,omitempty"`
+	// Detected is the license id found by licensing.Detect when Status is
+	// statusMalformed, i.e. the header the file actually carries.
+	Detected string `json:"detected,omitempty"`
+}
+
+// Reporter renders a set of dry-run results to out. Only offending files
+// (Status != statusOK) are passed in.
+type Reporter interface {
+	Report(out io.Writer, results []reportResult) error
+}
+
+// reporterFor returns the Reporter for the named -format flag value.
+func reporterFor(format string) (Reporter, error) {
+	switch format {
+	case "", "text":
+		return textReporter{}, nil
+	case "json":
+		return jsonReporter{}, nil
+	case "sarif":
+		return sarifReporter{}, nil
+	default:
+		return nil, fmt.Errorf("unknown format: %s", format)
+	}
+}
+
+// textReporter reproduces go-licenser's original dry-run output for files
+// missing a header, and prints a line naming the found and expected
+// license for files with a mismatched one.
+type textReporter struct{}
+
+func (textReporter) Report(out io.Writer, results []reportResult) error {
+	for _, r := range results {
+		if r.Status == statusMalformed {
+			if _, err := fmt.Fprintf(out, "%s: has a mismatched license header (found %s, expected %s)\n", r.Path, r.Detected, r.Expected); err != nil {
+				return err
+			}
+			continue
+		}
+		if _, err := fmt.Fprintf(out, defaultFormat, r.Path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type jsonReporter struct{}
+
+func (jsonReporter) Report(out io.Writer, results []reportResult) error {
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(results)
+}
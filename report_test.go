@@ -0,0 +1,113 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func testResults() []reportResult {
+	return []reportResult{
+		{Path: "a.go", Status: statusMissing, Expected: "ASL2"},
+		{Path: "b.go", Status: statusMalformed, Expected: "ASL2", Detected: "MIT"},
+	}
+}
+
+func TestReporterFor(t *testing.T) {
+	tests := []struct {
+		format  string
+		want    Reporter
+		wantErr bool
+	}{
+		{"", textReporter{}, false},
+		{"text", textReporter{}, false},
+		{"json", jsonReporter{}, false},
+		{"sarif", sarifReporter{}, false},
+		{"xml", nil, true},
+	}
+
+	for _, tt := range tests {
+		got, err := reporterFor(tt.format)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("reporterFor(%q) error = %v, wantErr %v", tt.format, err, tt.wantErr)
+			continue
+		}
+		if err == nil && got != tt.want {
+			t.Errorf("reporterFor(%q) = %#v, want %#v", tt.format, got, tt.want)
+		}
+	}
+}
+
+func TestTextReporter(t *testing.T) {
+	var out bytes.Buffer
+	if err := (textReporter{}).Report(&out, testResults()); err != nil {
+		t.Fatal(err)
+	}
+
+	got := out.String()
+	if !strings.Contains(got, "a.go: is missing the license header") {
+		t.Errorf("missing line for a.go, got: %s", got)
+	}
+	if !strings.Contains(got, "b.go: has a mismatched license header (found MIT, expected ASL2)") {
+		t.Errorf("missing malformed line for b.go, got: %s", got)
+	}
+}
+
+func TestJSONReporter(t *testing.T) {
+	var out bytes.Buffer
+	if err := (jsonReporter{}).Report(&out, testResults()); err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded []reportResult
+	if err := json.Unmarshal(out.Bytes(), &decoded); err != nil {
+		t.Fatalf("output is not valid JSON: %v\n%s", err, out.String())
+	}
+	if len(decoded) != 2 || decoded[1].Detected != "MIT" {
+		t.Errorf("decoded = %+v, want the malformed result to carry Detected=MIT", decoded)
+	}
+}
+
+func TestSarifReporter(t *testing.T) {
+	var out bytes.Buffer
+	if err := (sarifReporter{}).Report(&out, testResults()); err != nil {
+		t.Fatal(err)
+	}
+
+	var log sarifLog
+	if err := json.Unmarshal(out.Bytes(), &log); err != nil {
+		t.Fatalf("output is not valid SARIF JSON: %v\n%s", err, out.String())
+	}
+	if len(log.Runs) != 1 || len(log.Runs[0].Results) != 2 {
+		t.Fatalf("unexpected SARIF shape: %+v", log)
+	}
+
+	missing, malformed := log.Runs[0].Results[0], log.Runs[0].Results[1]
+	if !strings.Contains(missing.Message.Text, "is missing the license header") {
+		t.Errorf("missing result message = %q", missing.Message.Text)
+	}
+	if strings.Contains(malformed.Message.Text, "is missing the license header") {
+		t.Errorf("malformed result should not claim the header is missing, got: %q", malformed.Message.Text)
+	}
+	if !strings.Contains(malformed.Message.Text, "mismatched license header") {
+		t.Errorf("malformed result message = %q", malformed.Message.Text)
+	}
+}
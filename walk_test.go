@@ -0,0 +1,55 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestWalkSkipsOnlyTheExcludedFile is a regression test for a bug where
+// excluding a single file (via -ignore or .gitignore) caused
+// filepath.WalkDir's SkipDir to skip every remaining file in that
+// directory, not just the excluded one.
+func TestWalkSkipsOnlyTheExcludedFile(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.pb.go"), []byte("package p\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "b.go"), []byte("package p\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	err := run([]string{dir}, "ASL2", "Test Holder", "2024", "",
+		[]string{"*.pb.go"}, []string{".go"},
+		true /* dry */, false /* spdx */, 1, true /* noGitignore */, "json", false, 0.9, &out)
+
+	if err == nil {
+		t.Fatal("expected run to report b.go as missing its header")
+	}
+	if !strings.Contains(out.String(), "b.go") {
+		t.Errorf("report missing b.go, got: %s", out.String())
+	}
+	if strings.Contains(out.String(), "a.pb.go") {
+		t.Errorf("excluded a.pb.go should not appear in report, got: %s", out.String())
+	}
+}